@@ -0,0 +1,328 @@
+package shift
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// sourceKind identifies one layer in a Builder's pipeline.
+type sourceKind int
+
+const (
+	sourceDefaults sourceKind = iota
+	sourceFile
+	sourceEnv
+	sourceFlags
+)
+
+// Builder accumulates configuration sources in priority order - each one
+// added overrides the ones added before it - then binds them onto a struct
+// in a single Load call. It generalizes shift's historical fixed "env beats
+// file" rule into an explicit, user-ordered pipeline, e.g.:
+//
+//	err := shift.New().
+//		WithDefaults().
+//		WithFile("config.toml", "dev").
+//		WithEnv("APP").
+//		WithFlags(flag.CommandLine).
+//		Load(&c)
+//
+// Required and validate struct tags behave exactly as they do for the
+// package-level Load, and nested struct fields are recursed into the same
+// way. A `default:"..."` tag is only read by the WithDefaults source, at the
+// position it was added; without WithDefaults, defaults are not applied.
+type Builder struct {
+	order []sourceKind
+
+	file    string
+	fileEnv string
+
+	envPrefix string
+
+	flagSet *flag.FlagSet
+}
+
+// New returns an empty Builder. Chain WithDefaults, WithFile, WithEnv and
+// WithFlags to add sources in priority order, then call Load.
+func New() *Builder {
+	return &Builder{}
+}
+
+// WithDefaults adds a source that resolves a field from its `default:"..."`
+// struct tag, the same as the package-level Load does when no other source
+// supplies a value. It's typically the first source added, so every other
+// source overrides it.
+func (b *Builder) WithDefaults() *Builder {
+	b.order = append(b.order, sourceDefaults)
+	return b
+}
+
+// WithFile adds a source that resolves fields from file, decoded by
+// whichever FileDecoder is registered for its extension (see
+// RegisterDecoder), with values scoped to env exactly as the package-level
+// Load does.
+func (b *Builder) WithFile(file, env string) *Builder {
+	b.file = file
+	b.fileEnv = env
+	b.order = append(b.order, sourceFile)
+	return b
+}
+
+// WithEnv adds a source that resolves fields from the environment, using
+// prefix exactly as the package-level Load does.
+func (b *Builder) WithEnv(prefix string) *Builder {
+	b.envPrefix = prefix
+	b.order = append(b.order, sourceEnv)
+	return b
+}
+
+// WithFlags adds a source that resolves fields from command-line flags. A
+// flag.Flag is auto-registered on fs for every leaf field, named like its
+// env key but without the WithEnv prefix, lowercased and with dashes
+// instead of underscores (so a nested DB.Host field reads from --db-host). Registering a flag that fs
+// already has defined (because the caller registered their own under that
+// name) is skipped, leaving the caller's flag in place.
+//
+// The struct-derived flags don't exist on fs until Load runs, so fs must
+// not be parsed before then - Load registers them and parses fs itself,
+// with os.Args[1:]. Callers that need a different argument slice (tests,
+// mainly) can swap os.Args for the duration of the Load call.
+func (b *Builder) WithFlags(fs *flag.FlagSet) *Builder {
+	b.flagSet = fs
+	b.order = append(b.order, sourceFlags)
+	return b
+}
+
+// Load resolves every source added to b, earliest first, and binds the
+// result onto c the same way the package-level Load does, including
+// default/required/validate tag support and recursion into nested structs.
+func (b *Builder) Load(c interface{}) error {
+	typ := reflect.TypeOf(c)
+	if typ.Kind() != reflect.Ptr {
+		return errors.Errorf("'c' must be a pointer to a struct, was: %v", typ.String())
+	}
+	typ = typ.Elem()
+	if typ.Kind() != reflect.Struct {
+		return errors.Errorf("'c' must be a pointer to a struct, was: %v", typ.String())
+	}
+	val := reflect.Indirect(reflect.ValueOf(c))
+
+	fileConfig, err := b.loadFileConfig()
+	if err != nil {
+		return err
+	}
+
+	flagValues := map[string]*string{}
+	if b.flagSet != nil {
+		registerFlags(b.flagSet, "", typ, flagValues)
+		if err := b.flagSet.Parse(os.Args[1:]); err != nil {
+			return err
+		}
+	}
+
+	return bindPipeline(b.order, b.envPrefix, "", typ, val, fileConfig, flagValues)
+}
+
+// loadFileConfig decodes b.file exactly as the package-level Load does,
+// returning nil if no file source was added.
+func (b *Builder) loadFileConfig() (map[string]interface{}, error) {
+	if len(b.file) == 0 {
+		return nil, nil
+	}
+
+	dec, ok := decoders[strings.ToLower(filepath.Ext(b.file))]
+	if !ok {
+		dec = decoders[".toml"]
+	}
+
+	var i interface{}
+	if err := dec.Decode(b.file, &i); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if i == nil {
+		return nil, nil
+	}
+
+	if dec.Format() == "dotenv" {
+		m, _ := i.(map[string]interface{})
+		return m, nil
+	}
+
+	topLevel, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	envLevel, ok := topLevel[b.fileEnv]
+	if !ok {
+		return nil, nil
+	}
+	m, _ := envLevel.(map[string]interface{})
+	return m, nil
+}
+
+// registerFlags walks typ's fields, recursing into nested structs the same
+// way bind does, and registers a string flag on fs for every leaf field,
+// named flagPrefix joined to the field's key with a dash (underscores in
+// the key are themselves turned into dashes, so "db_url" becomes
+// "--db-url"). Flags fs already has defined are left alone.
+func registerFlags(fs *flag.FlagSet, flagPrefix string, typ reflect.Type, out map[string]*string) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		names := getKeyNames(f)
+		if len(names) == 0 {
+			continue
+		}
+
+		flagName := strings.ReplaceAll(names[0], "_", "-")
+		if len(flagPrefix) != 0 {
+			flagName = flagPrefix + "-" + flagName
+		}
+
+		isSetter := reflect.PtrTo(f.Type).Implements(setterType)
+		if f.Type.Kind() == reflect.Struct && f.Type != typeTime && !isSetter {
+			registerFlags(fs, flagName, f.Type, out)
+			continue
+		}
+
+		if fs.Lookup(flagName) != nil {
+			continue
+		}
+		out[flagName] = fs.String(flagName, "", fmt.Sprintf("overrides %s", names[0]))
+	}
+}
+
+// bindPipeline is bind's counterpart for Builder-driven loads: instead of
+// the fixed "env beats file" rule it walks order and lets whichever source
+// was added last win, falling back to required/validate handling exactly
+// like bind.
+func bindPipeline(order []sourceKind, envPrefix, flagPrefix string, typ reflect.Type, val reflect.Value, fileConfig map[string]interface{}, flagValues map[string]*string) error {
+	missing, err := bindPipelineCollectMissing(order, envPrefix, flagPrefix, typ, val, fileConfig, flagValues)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) != 0 {
+		return errors.Errorf("missing required key(s): %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// bindPipelineCollectMissing does the actual work of bindPipeline, mirroring
+// bind/bindCollectMissing's split so a nested struct's missing required
+// keys are merged into the parent's list (with the nested field's key
+// dotted on front) instead of aborting the whole bind on first miss.
+func bindPipelineCollectMissing(order []sourceKind, envPrefix, flagPrefix string, typ reflect.Type, val reflect.Value, fileConfig map[string]interface{}, flagValues map[string]*string) ([]string, error) {
+	n := typ.NumField()
+	var missing []string
+	for i := 0; i < n; i++ {
+		f := typ.Field(i)
+		names := getKeyNames(f)
+		if len(names) == 0 {
+			continue
+		}
+		key := names[0]
+		fieldVal := val.Field(i)
+
+		_, isSetter := asSetter(fieldVal)
+		if f.Type.Kind() == reflect.Struct && f.Type != typeTime && !isSetter {
+			nestedEnvPrefix := key
+			if len(envPrefix) != 0 {
+				nestedEnvPrefix = fmt.Sprintf("%s_%s", envPrefix, key)
+			}
+			nestedFlagPrefix := strings.ReplaceAll(key, "_", "-")
+			if len(flagPrefix) != 0 {
+				nestedFlagPrefix = flagPrefix + "-" + nestedFlagPrefix
+			}
+			nestedConfig, _ := fileConfig[key].(map[string]interface{})
+			nestedMissing, err := bindPipelineCollectMissing(order, nestedEnvPrefix, nestedFlagPrefix, f.Type, fieldVal, nestedConfig, flagValues)
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range nestedMissing {
+				missing = append(missing, key+"."+m)
+			}
+			continue
+		}
+
+		sep := f.Tag.Get("shift-sep")
+		if len(sep) == 0 {
+			sep = defaultSliceSep
+		}
+
+		resolved := false
+		for _, kind := range order {
+			switch kind {
+			case sourceDefaults:
+				def, ok := f.Tag.Lookup("default")
+				if !ok {
+					continue
+				}
+				if err := assignFromEnv(def, f.Type, fieldVal, sep); err != nil {
+					return nil, errors.Wrapf(err, "failed to assign default for key %s", key)
+				}
+				resolved = true
+			case sourceFile:
+				intf, ok := fileConfig[key]
+				if !ok {
+					continue
+				}
+				if err := assignFromIntf(intf, f.Type, fieldVal, sep); err != nil {
+					return nil, errors.Wrapf(err, "failed to assign key %s", key)
+				}
+				resolved = true
+			case sourceEnv:
+				var envVal string
+				for _, name := range names {
+					envKey := name
+					if len(envPrefix) != 0 {
+						envKey = fmt.Sprintf("%s_%s", envPrefix, envKey)
+					}
+					envVal = os.Getenv(strings.ToUpper(envKey))
+					if len(envVal) != 0 {
+						break
+					}
+				}
+				if len(envVal) == 0 {
+					continue
+				}
+				if err := assignFromEnv(envVal, f.Type, fieldVal, sep); err != nil {
+					return nil, errors.Wrapf(err, "failed to assign key %s", key)
+				}
+				resolved = true
+			case sourceFlags:
+				flagName := strings.ReplaceAll(key, "_", "-")
+				if len(flagPrefix) != 0 {
+					flagName = flagPrefix + "-" + flagName
+				}
+				flagVal, ok := flagValues[flagName]
+				if !ok || flagVal == nil || len(*flagVal) == 0 {
+					continue
+				}
+				if err := assignFromEnv(*flagVal, f.Type, fieldVal, sep); err != nil {
+					return nil, errors.Wrapf(err, "failed to assign flag --%s", flagName)
+				}
+				resolved = true
+			}
+		}
+
+		if !resolved && f.Tag.Get("required") == "true" {
+			missing = append(missing, key)
+			continue
+		}
+
+		if validateTag := f.Tag.Get("validate"); len(validateTag) != 0 {
+			if err := validateField(validateTag, key, fieldVal); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return missing, nil
+}
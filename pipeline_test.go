@@ -0,0 +1,98 @@
+package shift
+
+import (
+	"flag"
+	"os"
+	"testing"
+)
+
+type pipelineTestStruct struct {
+	Port int    `default:"8080"`
+	Host string `shift:"host"`
+	DB   struct {
+		Host string
+	}
+}
+
+// withArgs swaps os.Args for the duration of fn, restoring it afterwards.
+// Load parses its FlagSet from os.Args[1:] itself, so tests that want to
+// simulate command-line flags have to go through os.Args rather than
+// pre-parsing the FlagSet, which would fail since the struct-derived flags
+// aren't registered until Load runs.
+func withArgs(t *testing.T, args []string, fn func()) {
+	t.Helper()
+	old := os.Args
+	os.Args = append([]string{"test"}, args...)
+	defer func() { os.Args = old }()
+	fn()
+}
+
+func TestBuilderPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	file := dir + "/config.toml"
+	if err := os.WriteFile(file, []byte("[dev]\nhost = \"file-host\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("APP_HOST", "env-host")
+	defer os.Setenv("APP_HOST", "")
+
+	var c pipelineTestStruct
+	withArgs(t, []string{"--host=flag-host"}, func() {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		err := New().
+			WithDefaults().
+			WithFile(file, "dev").
+			WithEnv("APP").
+			WithFlags(fs).
+			Load(&c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if c.Port != 8080 {
+		t.Error("default wasn't applied:", c.Port)
+	}
+	if c.Host != "flag-host" {
+		t.Error("flags didn't win over env/file/defaults:", c.Host)
+	}
+}
+
+func TestBuilderFileUsedWhenNoFlagPassed(t *testing.T) {
+	dir := t.TempDir()
+	file := dir + "/config.toml"
+	if err := os.WriteFile(file, []byte("[dev]\nhost = \"file-host\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var c pipelineTestStruct
+	withArgs(t, nil, func() {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		err := New().
+			WithFile(file, "dev").
+			WithFlags(fs).
+			Load(&c)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if c.Host != "file-host" {
+		t.Error("file value wasn't bound when no flag was passed:", c.Host)
+	}
+}
+
+func TestBuilderNestedFlag(t *testing.T) {
+	var c pipelineTestStruct
+	withArgs(t, []string{"--db-host=nested-flag-host"}, func() {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		if err := New().WithFlags(fs).Load(&c); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if c.DB.Host != "nested-flag-host" {
+		t.Error("nested field wasn't bound from its dash-joined flag name:", c.DB.Host)
+	}
+}
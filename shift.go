@@ -5,10 +5,14 @@
 package shift
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"math"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -17,6 +21,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
 )
 
 var (
@@ -25,23 +30,247 @@ var (
 	typeTime      = reflect.TypeOf(time.Now())
 	typeDuration  = reflect.TypeOf(time.Duration(0))
 	typeStringArr = reflect.TypeOf([]string{})
+	typeLocation  = reflect.TypeOf(&time.Location{})
+	typeURL       = reflect.TypeOf(&url.URL{})
+
+	setterType = reflect.TypeOf((*Setter)(nil)).Elem()
 
 	sizeOfInt = int(reflect.TypeOf(int(0)).Size())
+
+	// decoders maps a file extension (including the leading dot) to the
+	// FileDecoder responsible for it. ".toml" is always registered and is
+	// also the fallback used for files with an unrecognized or missing
+	// extension, preserving shift's original behavior.
+	decoders = map[string]FileDecoder{}
 )
 
+func init() {
+	RegisterDecoder(".toml", tomlDecoder{})
+	RegisterDecoder(".yaml", yamlDecoder{})
+	RegisterDecoder(".yml", yamlDecoder{})
+	RegisterDecoder(".json", jsonDecoder{})
+	RegisterDecoder(".env", dotenvDecoder{})
+}
+
+// FileDecoder knows how to decode a particular configuration file format
+// into a generic interface{} suitable for binding onto a struct. Decode
+// should behave like toml.DecodeFile: a missing file is reported through
+// the normal os.IsNotExist check, not swallowed.
+type FileDecoder interface {
+	// Format returns the name of the format this decoder handles, e.g.
+	// "toml", "yaml", "json" or "dotenv".
+	Format() string
+	// Decode reads path and populates into, which is always a pointer to
+	// an interface{}.
+	Decode(path string, into interface{}) error
+}
+
+// RegisterDecoder associates a FileDecoder with a file extension (including
+// the leading dot, e.g. ".yaml"). Registering a decoder for an extension
+// that's already registered replaces the existing one.
+func RegisterDecoder(ext string, d FileDecoder) {
+	decoders[ext] = d
+}
+
+// Setter lets a user-defined type plug in its own parsing, taking priority
+// over shift's built-in handling for every other type. A field (or a
+// pointer to it, matching Go's usual addressable-receiver rules) implements
+// Setter by accepting the raw string form of its value, whether that value
+// came from ENV or from the config file.
+type Setter interface {
+	SetValue(string) error
+}
+
+// defaultSliceSep is used to split a string into slice/map elements when a
+// field has no `shift-sep` tag of its own.
+const defaultSliceSep = ","
+
+type tomlDecoder struct{}
+
+func (tomlDecoder) Format() string { return "toml" }
+func (tomlDecoder) Decode(path string, into interface{}) error {
+	_, err := testHarnessDecodeFile(path, into)
+	return err
+}
+
+type yamlDecoder struct{}
+
+func (yamlDecoder) Format() string { return "yaml" }
+func (yamlDecoder) Decode(path string, into interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	ptr, ok := into.(*interface{})
+	if !ok {
+		return errors.Errorf("yamlDecoder: into must be *interface{}, was %T", into)
+	}
+	*ptr = normalizeDecoded(normalizeYAML(raw))
+
+	return nil
+}
+
+// normalizeYAML converts the map[interface{}]interface{} values produced by
+// gopkg.in/yaml.v2 into map[string]interface{}, matching the shape the JSON
+// and TOML decoders already produce so bind's assumptions hold regardless of
+// file format.
+func normalizeYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[fmt.Sprintf("%v", key)] = normalizeYAML(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, val := range v {
+			s[i] = normalizeYAML(val)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// normalizeDecoded walks a value decoded by the JSON or YAML decoders and
+// converts their native number types (plain int from yaml.v2, float64 for
+// every number from encoding/json) into the int64/float64 shape
+// BurntSushi/toml already produces, so assignFromIntf only ever has to
+// handle one pair of numeric types regardless of file format. A float64
+// that holds a whole number is treated as an int64, so "5.0" in a JSON or
+// YAML file decodes the same as "5" (float64 fields accept either).
+func normalizeDecoded(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[key] = normalizeDecoded(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, val := range v {
+			s[i] = normalizeDecoded(val)
+		}
+		return s
+	case int:
+		return int64(v)
+	case float64:
+		if v == math.Trunc(v) {
+			return int64(v)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+type jsonDecoder struct{}
+
+func (jsonDecoder) Format() string { return "json" }
+func (jsonDecoder) Decode(path string, into interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, into); err != nil {
+		return err
+	}
+
+	if ptr, ok := into.(*interface{}); ok {
+		*ptr = normalizeDecoded(*ptr)
+	}
+	return nil
+}
+
+// dotenvDecoder decodes a ".env" file of KEY=VALUE lines. Unlike the other
+// formats it has no concept of environment sections - every key it finds is
+// treated as belonging to whichever "env" Load was called with.
+type dotenvDecoder struct{}
+
+func (dotenvDecoder) Format() string { return "dotenv" }
+func (dotenvDecoder) Decode(path string, into interface{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	m := make(map[string]interface{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(kv[0])
+		val := strings.Trim(strings.TrimSpace(kv[1]), `"'`)
+		m[key] = val
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	ptr, ok := into.(*interface{})
+	if !ok {
+		return errors.Errorf("dotenvDecoder: into must be *interface{}, was %T", into)
+	}
+	*ptr = m
+
+	return nil
+}
+
 // Load finds key names from the struct tags in c and tries to load them
 // from various sources.
 //
-// The values that are loaded from the file must be divided in sections for each
-// "environment" - that's to say everything must be under top level keys that
-// name the environments.
+// file is decoded by the FileDecoder registered for its extension
+// (".toml", ".yaml"/".yml", ".json" and ".env" are registered by default,
+// see RegisterDecoder). Files with an unrecognized or missing extension
+// fall back to the TOML decoder. The values loaded from the file must be
+// divided in sections for each "environment" - that's to say everything
+// must be under top level keys that name the environments - except for
+// ".env" files, which have no sections and are loaded as the "env" layer
+// directly.
 //
 // Only a few value types are supported:
 // - bool
-// - string / []string
-// - int / int64 / uint / uint64
-// - time.Time (RFC3339)
-// - time.Duration
+// - string / float64 / int / int64 / uint / uint64
+// - time.Time (RFC3339) / time.Duration
+// - *time.Location (time.LoadLocation) / *url.URL (url.Parse)
+// - slices of any of the above scalar types, split on a separator ("," by
+//   default, override with a `shift-sep:"..."` tag)
+// - map[string]T where T is one of the above scalars, entries of the form
+//   "key:value" separated the same way as slices
+//
+// Any other type can plug into assignment by implementing the Setter
+// interface on a pointer receiver.
+//
+// A field may also carry a `default:"..."` tag, used when neither ENV nor
+// the file supplies a value, a `required:"true"` tag, which (if the field
+// is still unresolved after defaults) is collected into a single aggregated
+// error rather than failing on the first missing key, and a `validate:"..."`
+// tag (see validateField) that runs once a value has been assigned.
+//
+// A struct-typed field other than time.Time is treated as a nested group
+// rather than a value to assign directly: its own fields are bound using an
+// env prefix of the field's key appended to envPrefix, and a file key of the
+// field's key looked up as a nested table in config. So given envPrefix
+// "APP" and a `DB struct { Host string }` field, Host is read from
+// APP_DB_HOST or the file's "db.host". A `shift:"..."` tag on the struct
+// field overrides the key used for both, just as it does for scalar fields.
 //
 // Earlier sources are overidden by later sources in this list:
 // 1. ENV
@@ -57,18 +286,25 @@ func Load(c interface{}, file, envPrefix, env string) error {
 	}
 	val := reflect.Indirect(reflect.ValueOf(c))
 
+	dec, ok := decoders[strings.ToLower(filepath.Ext(file))]
+	if !ok {
+		dec = decoders[".toml"]
+	}
+
 	var i interface{}
-	_, err := testHarnessDecodeFile(file, &i)
+	err := dec.Decode(file, &i)
 	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
 
 	var m map[string]interface{}
 	if i != nil {
-		topLevel := i.(map[string]interface{})
-		if topLevel != nil {
-			envLevel := topLevel[env]
-			m = envLevel.(map[string]interface{})
+		if dec.Format() == "dotenv" {
+			m = i.(map[string]interface{})
+		} else if topLevel, ok := i.(map[string]interface{}); ok {
+			if envLevel, ok := topLevel[env]; ok {
+				m, _ = envLevel.(map[string]interface{})
+			}
 		}
 	}
 
@@ -76,39 +312,209 @@ func Load(c interface{}, file, envPrefix, env string) error {
 }
 
 func bind(envPrefix string, typ reflect.Type, val reflect.Value, config map[string]interface{}) error {
+	missing, err := bindCollectMissing(envPrefix, typ, val, config)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) != 0 {
+		return errors.Errorf("missing required key(s): %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// bindCollectMissing does the actual work of bind, but instead of failing
+// as soon as a nested struct reports a missing required key, it returns
+// that key (prefixed with the nested field's own key, dotted) to its
+// caller so binding can keep going - otherwise a required field declared
+// after a nested struct in the same parent would never even be visited,
+// breaking the aggregated-missing-keys contract Load promises.
+func bindCollectMissing(envPrefix string, typ reflect.Type, val reflect.Value, config map[string]interface{}) ([]string, error) {
 	n := typ.NumField()
+	var missing []string
 	for i := 0; i < n; i++ {
 		f := typ.Field(i)
-		key := getKeyName(f)
+		names := getKeyNames(f)
 
-		if len(key) == 0 {
+		if len(names) == 0 {
 			continue
 		}
+		key := names[0]
+		fieldVal := val.Field(i)
 
-		envKey := key
-		if len(envPrefix) != 0 {
-			envKey = fmt.Sprintf("%s_%s", envPrefix, envKey)
+		_, isSetter := asSetter(fieldVal)
+		if f.Type.Kind() == reflect.Struct && f.Type != typeTime && !isSetter {
+			nestedPrefix := key
+			nestedEnvPrefix := nestedPrefix
+			if len(envPrefix) != 0 {
+				nestedEnvPrefix = fmt.Sprintf("%s_%s", envPrefix, nestedPrefix)
+			}
+			nestedConfig, _ := config[key].(map[string]interface{})
+			nestedMissing, err := bindCollectMissing(nestedEnvPrefix, f.Type, fieldVal, nestedConfig)
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range nestedMissing {
+				missing = append(missing, key+"."+m)
+			}
+			continue
+		}
+
+		sep := f.Tag.Get("shift-sep")
+		if len(sep) == 0 {
+			sep = defaultSliceSep
+		}
+
+		resolved := false
+
+		var envVal string
+		for _, name := range names {
+			envKey := name
+			if len(envPrefix) != 0 {
+				envKey = fmt.Sprintf("%s_%s", envPrefix, envKey)
+			}
+			envVal = os.Getenv(strings.ToUpper(envKey))
+			if len(envVal) != 0 {
+				break
+			}
 		}
-		envVal := os.Getenv(strings.ToUpper(envKey))
 		if len(envVal) != 0 {
-			if err := assignFromEnv(envVal, f.Type, val.Field(i)); err != nil {
-				return errors.Wrapf(err, "failed to assign key %s", key)
+			if err := assignFromEnv(envVal, f.Type, fieldVal, sep); err != nil {
+				return nil, errors.Wrapf(err, "failed to assign key %s", key)
+			}
+			resolved = true
+		}
+
+		if !resolved {
+			if intf, ok := config[key]; ok {
+				if err := assignFromIntf(intf, f.Type, fieldVal, sep); err != nil {
+					return nil, errors.Wrapf(err, "failed to assign key %s", key)
+				}
+				resolved = true
+			}
+		}
+
+		if !resolved {
+			if def, ok := f.Tag.Lookup("default"); ok {
+				if err := assignFromEnv(def, f.Type, fieldVal, sep); err != nil {
+					return nil, errors.Wrapf(err, "failed to assign default for key %s", key)
+				}
+				resolved = true
 			}
+		}
+
+		if !resolved && f.Tag.Get("required") == "true" {
+			missing = append(missing, key)
 			continue
 		}
 
-		if intf, ok := config[key]; ok {
-			if err := assignFromIntf(intf, f.Type, val.Field(i)); err != nil {
-				return errors.Wrapf(err, "failed to assign key %s", key)
+		if validateTag := f.Tag.Get("validate"); len(validateTag) != 0 {
+			if err := validateField(validateTag, key, fieldVal); err != nil {
+				return nil, err
 			}
+		}
+	}
+
+	return missing, nil
+}
+
+// validateField runs the comma-separated rules in tag (as found in a
+// `validate:"..."` struct tag) against fieldVal, returning the first
+// violation encountered. Supported rules: "nonempty", "oneof=a|b|c",
+// "min=N", "max=N" and "url".
+func validateField(tag, key string, fieldVal reflect.Value) error {
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		if len(rule) == 0 {
 			continue
 		}
+
+		name, arg := rule, ""
+		if idx := strings.Index(rule, "="); idx != -1 {
+			name, arg = rule[:idx], rule[idx+1:]
+		}
+
+		switch name {
+		case "nonempty":
+			if fieldVal.IsZero() {
+				return errors.Errorf("validation failed for key %s: must not be empty", key)
+			}
+		case "oneof":
+			if fieldVal.Kind() != reflect.String {
+				return errors.Errorf("validation failed for key %s: oneof only supports strings", key)
+			}
+			s := fieldVal.String()
+			var found bool
+			for _, opt := range strings.Split(arg, "|") {
+				if opt == s {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return errors.Errorf("validation failed for key %s: %q must be one of %s", key, s, arg)
+			}
+		case "min":
+			if err := validateBound(key, "min", arg, fieldVal, false); err != nil {
+				return err
+			}
+		case "max":
+			if err := validateBound(key, "max", arg, fieldVal, true); err != nil {
+				return err
+			}
+		case "url":
+			if fieldVal.Kind() != reflect.String {
+				return errors.Errorf("validation failed for key %s: url only supports strings", key)
+			}
+			if _, err := url.Parse(fieldVal.String()); err != nil || len(fieldVal.String()) == 0 {
+				return errors.Errorf("validation failed for key %s: not a valid url", key)
+			}
+		default:
+			return errors.Errorf("unknown validator %q for key %s", name, key)
+		}
 	}
 
 	return nil
 }
 
-func assignFromEnv(envVal string, fieldType reflect.Type, fieldVal reflect.Value) error {
+// validateBound enforces a min/max rule. Strings are measured by length;
+// numeric kinds are compared by value. isMax flips the comparison direction.
+func validateBound(key, rule, arg string, fieldVal reflect.Value, isMax bool) error {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid %s bound %q for key %s", rule, arg, key)
+	}
+
+	var actual float64
+	switch fieldVal.Kind() {
+	case reflect.String:
+		actual = float64(len(fieldVal.String()))
+	case reflect.Int, reflect.Int64:
+		actual = float64(fieldVal.Int())
+	case reflect.Uint, reflect.Uint64:
+		actual = float64(fieldVal.Uint())
+	case reflect.Float64:
+		actual = fieldVal.Float()
+	default:
+		return errors.Errorf("validation failed for key %s: %s is unsupported for %s", key, rule, fieldVal.Kind())
+	}
+
+	if isMax && actual > bound {
+		return errors.Errorf("validation failed for key %s: %v exceeds max %v", key, actual, bound)
+	}
+	if !isMax && actual < bound {
+		return errors.Errorf("validation failed for key %s: %v is below min %v", key, actual, bound)
+	}
+
+	return nil
+}
+
+func assignFromEnv(envVal string, fieldType reflect.Type, fieldVal reflect.Value, sep string) error {
+	if setter, ok := asSetter(fieldVal); ok {
+		return setter.SetValue(envVal)
+	}
+
 	switch fieldType.Kind() {
 	case reflect.String:
 		fieldVal.SetString(envVal)
@@ -168,6 +574,36 @@ func assignFromEnv(envVal string, fieldType reflect.Type, fieldVal reflect.Value
 			return err
 		}
 		fieldVal.Set(reflect.ValueOf(date))
+	case reflect.Ptr:
+		switch fieldType {
+		case typeLocation:
+			loc, err := time.LoadLocation(envVal)
+			if err != nil {
+				return err
+			}
+			fieldVal.Set(reflect.ValueOf(loc))
+		case typeURL:
+			u, err := url.Parse(envVal)
+			if err != nil {
+				return err
+			}
+			fieldVal.Set(reflect.ValueOf(u))
+		default:
+			return errors.Errorf("unsupported pointer type: %s", fieldType.String())
+		}
+	case reflect.Slice:
+		parts := strings.Split(envVal, sep)
+		slice, err := parseSliceParts(parts, fieldType.Elem())
+		if err != nil {
+			return err
+		}
+		fieldVal.Set(slice)
+	case reflect.Map:
+		m, err := parseMapParts(envVal, sep, fieldType)
+		if err != nil {
+			return err
+		}
+		fieldVal.Set(m)
 	default:
 		return errors.Errorf("unsupported struct type: %s", fieldType.String())
 	}
@@ -175,7 +611,73 @@ func assignFromEnv(envVal string, fieldType reflect.Type, fieldVal reflect.Value
 	return nil
 }
 
-func assignFromIntf(val interface{}, fieldType reflect.Type, fieldVal reflect.Value) error {
+// asSetter reports whether fieldVal (or a pointer to it) implements Setter.
+func asSetter(fieldVal reflect.Value) (Setter, bool) {
+	if !fieldVal.CanAddr() {
+		return nil, false
+	}
+	addr := fieldVal.Addr()
+	if !addr.Type().Implements(setterType) {
+		return nil, false
+	}
+	return addr.Interface().(Setter), true
+}
+
+// parseSliceParts parses a list of strings into a new slice of elemType,
+// reusing assignFromEnv's scalar handling for each element.
+func parseSliceParts(parts []string, elemType reflect.Type) (reflect.Value, error) {
+	slice := reflect.MakeSlice(reflect.SliceOf(elemType), len(parts), len(parts))
+	for i, p := range parts {
+		if err := assignFromEnv(p, elemType, slice.Index(i), defaultSliceSep); err != nil {
+			return reflect.Value{}, errors.Wrapf(err, "failed to parse slice element %q", p)
+		}
+	}
+	return slice, nil
+}
+
+// parseMapParts parses a "k1:v1,k2:v2"-style string into a new map of
+// mapType, which must have a string key. Each value is parsed by
+// assignFromEnv, so any scalar kind it handles is supported.
+func parseMapParts(raw, sep string, mapType reflect.Type) (reflect.Value, error) {
+	if mapType.Key().Kind() != reflect.String {
+		return reflect.Value{}, errors.Errorf("unsupported map key type: %s", mapType.Key().String())
+	}
+
+	m := reflect.MakeMap(mapType)
+	if len(raw) == 0 {
+		return m, nil
+	}
+
+	for _, pair := range strings.Split(raw, sep) {
+		kv := strings.SplitN(pair, ":", 2)
+		if len(kv) != 2 {
+			return reflect.Value{}, errors.Errorf("invalid map entry %q, expected key:value", pair)
+		}
+
+		elemVal := reflect.New(mapType.Elem()).Elem()
+		if err := assignFromEnv(kv[1], mapType.Elem(), elemVal, defaultSliceSep); err != nil {
+			return reflect.Value{}, errors.Wrapf(err, "failed to parse map value for key %q", kv[0])
+		}
+		m.SetMapIndex(reflect.ValueOf(kv[0]), elemVal)
+	}
+
+	return m, nil
+}
+
+func assignFromIntf(val interface{}, fieldType reflect.Type, fieldVal reflect.Value, sep string) error {
+	if setter, ok := asSetter(fieldVal); ok {
+		return setter.SetValue(fmt.Sprintf("%v", val))
+	}
+
+	// The dotenv decoder has no concept of types - every value it produces
+	// is a plain string, the same shape assignFromEnv already knows how to
+	// turn into every supported field type (including time.Time, Duration,
+	// *time.Location, *url.URL, slices and maps). Route it there instead of
+	// re-implementing string parsing for every reflect.Kind below.
+	if s, ok := val.(string); ok && fieldType.Kind() != reflect.String {
+		return assignFromEnv(s, fieldType, fieldVal, sep)
+	}
+
 	switch fieldType.Kind() {
 	case reflect.String:
 		if s, ok := val.(string); ok {
@@ -196,23 +698,12 @@ func assignFromIntf(val interface{}, fieldType reflect.Type, fieldVal reflect.Va
 			return nil
 		}
 	case reflect.Int64:
-		if fieldType == typeDuration {
-			if s, ok := val.(string); ok {
-				d, err := time.ParseDuration(s)
-				if err != nil {
-					return err
-				}
-				fieldVal.Set(reflect.ValueOf(d))
-				return nil
-			}
-		} else {
-			if i, ok := val.(int64); ok {
-				if _, err := int64ToInt(i); err != nil {
-					return err
-				}
-				fieldVal.SetInt(i)
-				return nil
+		if i, ok := val.(int64); ok {
+			if _, err := int64ToInt(i); err != nil {
+				return err
 			}
+			fieldVal.SetInt(i)
+			return nil
 		}
 	case reflect.Uint:
 		if i, ok := val.(int64); ok {
@@ -228,9 +719,13 @@ func assignFromIntf(val interface{}, fieldType reflect.Type, fieldVal reflect.Va
 			return nil
 		}
 	case reflect.Float64:
-		if f, ok := val.(float64); ok {
+		switch f := val.(type) {
+		case float64:
 			fieldVal.SetFloat(f)
 			return nil
+		case int64:
+			fieldVal.SetFloat(float64(f))
+			return nil
 		}
 	case reflect.Struct:
 		if fieldType == typeTime {
@@ -238,32 +733,61 @@ func assignFromIntf(val interface{}, fieldType reflect.Type, fieldVal reflect.Va
 			return nil
 		}
 	case reflect.Slice:
-		if fieldType == typeStringArr {
-			if s, ok := val.([]interface{}); ok {
-				sArr := make([]string, len(s))
-				for i := range s {
-					str, _ := s[i].(string)
-					sArr[i] = str
+		if elems, ok := val.([]interface{}); ok {
+			slice := reflect.MakeSlice(fieldType, len(elems), len(elems))
+			for i := range elems {
+				if err := assignFromIntf(elems[i], fieldType.Elem(), slice.Index(i), sep); err != nil {
+					return err
 				}
-				fieldVal.Set(reflect.ValueOf(sArr))
-				return nil
 			}
+			fieldVal.Set(slice)
+			return nil
+		}
+	case reflect.Map:
+		if raw, ok := val.(map[string]interface{}); ok {
+			m := reflect.MakeMap(fieldType)
+			for k, v := range raw {
+				elemVal := reflect.New(fieldType.Elem()).Elem()
+				if err := assignFromIntf(v, fieldType.Elem(), elemVal, sep); err != nil {
+					return err
+				}
+				m.SetMapIndex(reflect.ValueOf(k), elemVal)
+			}
+			fieldVal.Set(m)
+			return nil
 		}
 	}
 
 	return errors.Errorf("unsupported conversion %s -> %s", fieldType.String(), reflect.TypeOf(val).String())
 }
 
-func getKeyName(f reflect.StructField) string {
+// getKeyNames returns the names a field can be known by: the file key
+// (always first) followed by zero or more additional env var aliases, all
+// drawn from a comma-separated `shift` tag (e.g. `shift:"db_url,database_url"`).
+// A bare `shift:"-"` or an empty result means the field is ignored entirely.
+func getKeyNames(f reflect.StructField) []string {
 	tag := f.Tag.Get("shift")
 	switch {
 	case tag == "-":
-		return ""
+		return nil
 	case len(tag) != 0:
-		return tag
+		parts := strings.Split(tag, ",")
+		for i, p := range parts {
+			parts[i] = strings.TrimSpace(p)
+		}
+		return parts
 	default:
-		return toCamel(f.Name)
+		return []string{toCamel(f.Name)}
+	}
+}
+
+// getKeyName returns the file key for f - the first name in its `shift` tag.
+func getKeyName(f reflect.StructField) string {
+	names := getKeyNames(f)
+	if len(names) == 0 {
+		return ""
 	}
+	return names[0]
 }
 
 // int64ToInt converts but also checks bounds to ensure it can fit
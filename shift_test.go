@@ -1,8 +1,10 @@
 package shift
 
 import (
+	"net/url"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -155,6 +157,248 @@ func TestGetKeyName(t *testing.T) {
 	}
 }
 
+func TestLoadMultipleEnvAliases(t *testing.T) {
+	var s = struct {
+		DBURL string `shift:"db_url,database_url,pg_url"`
+	}{}
+
+	os.Setenv("DATABASE_URL", "postgres://legacy")
+	defer os.Setenv("DATABASE_URL", "")
+
+	typ := reflect.TypeOf(s)
+	val := reflect.ValueOf(&s).Elem()
+
+	if err := bind("", typ, val, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.DBURL != "postgres://legacy" {
+		t.Error("DBURL wasn't bound from the second alias:", s.DBURL)
+	}
+}
+
+func TestGetKeyNames(t *testing.T) {
+	t.Parallel()
+
+	var s = struct {
+		Int    int
+		String string `shift:"a"`
+		Uint   uint   `shift:"-"`
+		Multi  string `shift:"db_url,database_url,pg_url"`
+	}{}
+
+	typ := reflect.TypeOf(s)
+
+	if got := getKeyNames(typ.Field(0)); !reflect.DeepEqual(got, []string{"int"}) {
+		t.Error("int wasn't found:", got)
+	}
+	if got := getKeyNames(typ.Field(1)); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Error("a wasn't found:", got)
+	}
+	if got := getKeyNames(typ.Field(2)); len(got) != 0 {
+		t.Error("uint should have no names:", got)
+	}
+	want := []string{"db_url", "database_url", "pg_url"}
+	if got := getKeyNames(typ.Field(3)); !reflect.DeepEqual(got, want) {
+		t.Error("multi names didn't match:", got)
+	}
+}
+
+func TestLoadDefault(t *testing.T) {
+	var s = struct {
+		Port int `default:"8080"`
+	}{}
+
+	if err := bind("", reflect.TypeOf(s), reflect.ValueOf(&s).Elem(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Port != 8080 {
+		t.Error("Port wasn't defaulted:", s.Port)
+	}
+}
+
+func TestLoadRequiredMissing(t *testing.T) {
+	var s = struct {
+		APIKey string `required:"true"`
+		Secret string `required:"true"`
+	}{}
+
+	err := bind("", reflect.TypeOf(s), reflect.ValueOf(&s).Elem(), nil)
+	if err == nil {
+		t.Fatal("expected an error for missing required keys")
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, "api_key") || !strings.Contains(msg, "secret") {
+		t.Error("expected both missing keys in the error, got:", msg)
+	}
+}
+
+func TestLoadRequiredMissingThroughNestedStruct(t *testing.T) {
+	type nestedReq struct {
+		Inner string `required:"true"`
+	}
+	var s = struct {
+		N nestedReq
+		B string `required:"true"`
+	}{}
+
+	err := bind("", reflect.TypeOf(s), reflect.ValueOf(&s).Elem(), nil)
+	if err == nil {
+		t.Fatal("expected an error for missing required keys")
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, "n.inner") || !strings.Contains(msg, ", b") {
+		t.Error("expected both the nested and top-level missing keys in the error, got:", msg)
+	}
+}
+
+func TestValidateField(t *testing.T) {
+	t.Parallel()
+
+	var s = struct {
+		Mode string
+		Port int
+	}{}
+	val := reflect.ValueOf(&s).Elem()
+
+	s.Mode = "prod"
+	if err := validateField("oneof=dev|test|prod", "mode", val.Field(0)); err != nil {
+		t.Error("expected prod to be valid:", err)
+	}
+
+	s.Mode = "staging"
+	if err := validateField("oneof=dev|test|prod", "mode", val.Field(0)); err == nil {
+		t.Error("expected staging to fail oneof validation")
+	}
+
+	s.Port = 80
+	if err := validateField("min=1024,max=65535", "port", val.Field(1)); err == nil {
+		t.Error("expected 80 to fail the min bound")
+	}
+
+	s.Port = 8080
+	if err := validateField("min=1024,max=65535", "port", val.Field(1)); err != nil {
+		t.Error("expected 8080 to satisfy the bounds:", err)
+	}
+}
+
+func TestLoadNestedStruct(t *testing.T) {
+	type dbConfig struct {
+		Host string
+		Port int
+	}
+	var s = struct {
+		DB dbConfig
+	}{}
+
+	os.Setenv("APP_DB_HOST", "envhost")
+	defer os.Setenv("APP_DB_HOST", "")
+
+	config := map[string]interface{}{
+		"db": map[string]interface{}{
+			"host": "filehost",
+			"port": int64(5432),
+		},
+	}
+
+	if err := bind("APP", reflect.TypeOf(s), reflect.ValueOf(&s).Elem(), config); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.DB.Host != "envhost" {
+		t.Error("expected ENV to win for DB.Host:", s.DB.Host)
+	}
+	if s.DB.Port != 5432 {
+		t.Error("expected DB.Port from the file's nested table:", s.DB.Port)
+	}
+}
+
+type csvValue struct {
+	parts []string
+}
+
+func (c *csvValue) SetValue(s string) error {
+	c.parts = strings.Split(s, ";")
+	return nil
+}
+
+func TestLoadSetter(t *testing.T) {
+	var s = struct {
+		CSV csvValue
+	}{}
+
+	os.Setenv("CSV", "a;b;c")
+	defer os.Setenv("CSV", "")
+
+	if err := bind("", reflect.TypeOf(s), reflect.ValueOf(&s).Elem(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(s.CSV.parts, want) {
+		t.Error("Setter wasn't used to parse CSV:", s.CSV.parts)
+	}
+}
+
+func TestLoadLocationAndURL(t *testing.T) {
+	var s = struct {
+		TZ  *time.Location `shift:"tz_var"`
+		API *url.URL       `shift:"api_var"`
+	}{}
+
+	os.Setenv("TZ_VAR", "America/New_York")
+	os.Setenv("API_VAR", "https://example.com/v1")
+	defer func() {
+		os.Setenv("TZ_VAR", "")
+		os.Setenv("API_VAR", "")
+	}()
+
+	if err := bind("", reflect.TypeOf(s), reflect.ValueOf(&s).Elem(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.TZ.String() != "America/New_York" {
+		t.Error("TZ wasn't loaded:", s.TZ)
+	}
+	if s.API.Host != "example.com" {
+		t.Error("API wasn't parsed:", s.API)
+	}
+}
+
+func TestLoadSliceAndMap(t *testing.T) {
+	var s = struct {
+		Ports   []int             `shift-sep:"|"`
+		Tags    map[string]string `shift-sep:"|"`
+		Weights map[string]int    `shift-sep:"|"`
+	}{}
+
+	os.Setenv("PORTS", "80|443|8080")
+	os.Setenv("TAGS", "env:prod|team:infra")
+	os.Setenv("WEIGHTS", "a:1|b:2")
+	defer func() {
+		os.Setenv("PORTS", "")
+		os.Setenv("TAGS", "")
+		os.Setenv("WEIGHTS", "")
+	}()
+
+	if err := bind("", reflect.TypeOf(s), reflect.ValueOf(&s).Elem(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(s.Ports, []int{80, 443, 8080}) {
+		t.Error("Ports wasn't parsed:", s.Ports)
+	}
+	if s.Tags["env"] != "prod" || s.Tags["team"] != "infra" {
+		t.Error("Tags wasn't parsed:", s.Tags)
+	}
+	if s.Weights["a"] != 1 || s.Weights["b"] != 2 {
+		t.Error("Weights wasn't parsed:", s.Weights)
+	}
+}
+
 func TestInt64ToInt(t *testing.T) {
 	t.Parallel()
 
@@ -207,6 +451,71 @@ func TestToCamel(t *testing.T) {
 	}
 }
 
+type testStructThree struct {
+	Configstring string
+	Configint    int
+}
+
+func TestLoadJSONDecoder(t *testing.T) {
+	dir := t.TempDir()
+	file := dir + "/config.json"
+	contents := `{"dev":{"configstring":"string","configint":-5}}`
+	if err := os.WriteFile(file, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := testStructThree{}
+	want := testStructThree{Configstring: "string", Configint: -5}
+
+	if err := Load(&got, file, "", "dev"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("didn't load keys properly:\nwant: %#v\ngot: %#v", want, got)
+	}
+}
+
+func TestLoadYAMLDecoder(t *testing.T) {
+	dir := t.TempDir()
+	file := dir + "/config.yaml"
+	contents := "dev:\n  configstring: string\n  configint: -5\n"
+	if err := os.WriteFile(file, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := testStructThree{}
+	want := testStructThree{Configstring: "string", Configint: -5}
+
+	if err := Load(&got, file, "", "dev"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("didn't load keys properly:\nwant: %#v\ngot: %#v", want, got)
+	}
+}
+
+func TestLoadDotenvDecoder(t *testing.T) {
+	dir := t.TempDir()
+	file := dir + "/config.env"
+	contents := "configstring=string\nconfigint=-5\n"
+	if err := os.WriteFile(file, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := testStructThree{}
+	want := testStructThree{Configstring: "string", Configint: -5}
+
+	if err := Load(&got, file, "", "dev"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("didn't load keys properly:\nwant: %#v\ngot: %#v", want, got)
+	}
+}
+
 var testTomlTwo = `
 [dev]
 	bind = ":3999"
@@ -0,0 +1,115 @@
+package shift
+
+import (
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long LoadAndWatch waits after the last filesystem
+// event before reloading, so that editors which emit several events per
+// save (write, then chmod, then rename-replace) only trigger one reload.
+const watchDebounce = 100 * time.Millisecond
+
+// LoadAndWatch calls Load to populate c, then watches file for changes and
+// re-runs Load into a fresh struct of c's type on every write, re-creating
+// it, or rename, copying the freshly loaded values over c's fields and
+// invoking onChange with the result. onChange is also called with any
+// error encountered along the way, including a failed reload - in that
+// case c is left holding its last-good values.
+//
+// Because fsnotify watches containing directories rather than individual
+// files, LoadAndWatch survives editors that save by writing a new file and
+// renaming it over the original: losing the watch on file because its
+// directory entry was replaced is detected and the watch is transparently
+// re-added.
+//
+// The returned stop function stops watching and releases the underlying
+// fsnotify watcher; it must be called once the caller is done with the
+// watch. LoadAndWatch does not synchronize reads of c against concurrent
+// reloads - callers that read c from other goroutines must add their own
+// locking around those reads and around onChange.
+func LoadAndWatch(c interface{}, file, envPrefix, env string, onChange func(error)) (stop func(), err error) {
+	if err := Load(c, file, envPrefix, env); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(file)
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	typ := reflect.TypeOf(c).Elem()
+	target := reflect.ValueOf(c).Elem()
+	done := make(chan struct{})
+
+	var mu sync.Mutex
+	reload := func() {
+		fresh := reflect.New(typ).Interface()
+		loadErr := Load(fresh, file, envPrefix, env)
+		if loadErr == nil {
+			mu.Lock()
+			target.Set(reflect.ValueOf(fresh).Elem())
+			mu.Unlock()
+		}
+		onChange(loadErr)
+	}
+
+	go func() {
+		var debounce *time.Timer
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(file) {
+					continue
+				}
+
+				if event.Op&fsnotify.Rename != 0 || event.Op&fsnotify.Remove != 0 {
+					// The directory entry for file was replaced or removed
+					// out from under the watch (common with editors that
+					// save by rename). Re-add the watch on its directory
+					// so subsequent saves are still seen.
+					watcher.Remove(dir)
+					if err := watcher.Add(dir); err != nil {
+						onChange(err)
+						continue
+					}
+				}
+
+				if debounce != nil {
+					debounce.Stop()
+				}
+				debounce = time.AfterFunc(watchDebounce, reload)
+			case watchErr, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				onChange(watchErr)
+			case <-done:
+				if debounce != nil {
+					debounce.Stop()
+				}
+				return
+			}
+		}
+	}()
+
+	stop = func() {
+		close(done)
+		watcher.Close()
+	}
+
+	return stop, nil
+}
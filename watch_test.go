@@ -0,0 +1,54 @@
+package shift
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+type watchTestStruct struct {
+	Configstring string
+}
+
+func TestLoadAndWatch(t *testing.T) {
+	dir := t.TempDir()
+	file := dir + "/config.toml"
+
+	write := func(val string) {
+		contents := "[dev]\nconfigstring = \"" + val + "\"\n"
+		if err := os.WriteFile(file, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("first")
+
+	got := watchTestStruct{}
+	changes := make(chan error, 4)
+
+	stop, err := LoadAndWatch(&got, file, "", "dev", func(err error) {
+		changes <- err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	if got.Configstring != "first" {
+		t.Fatal("initial load failed:", got.Configstring)
+	}
+
+	write("second")
+
+	select {
+	case err := <-changes:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reload")
+	}
+
+	if got.Configstring != "second" {
+		t.Error("reload didn't pick up the new value:", got.Configstring)
+	}
+}